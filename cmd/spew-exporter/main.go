@@ -0,0 +1,55 @@
+// Command spew-exporter runs a spew config as a Prometheus exporter: each
+// source declaring a Metric field is registered as a gauge or counter and
+// served on /metrics. The template is still rendered, but its output is
+// discarded.
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/BurntSushi/toml"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/kmwenja/spew"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Printf("Usage: spew-exporter <config-file> [listen-addr]\n")
+		os.Exit(1)
+	}
+
+	addr := ":9090"
+	if len(os.Args) > 2 {
+		addr = os.Args[2]
+	}
+
+	var c spew.Config
+	_, err := toml.DecodeFile(os.Args[1], &c)
+	if err != nil {
+		panic(fmt.Errorf("could not read from config file: %w", err))
+	}
+
+	registry := prometheus.NewRegistry()
+	sink, err := spew.NewPrometheusSink(registry, c.Sources)
+	if err != nil {
+		panic(fmt.Errorf("could not set up prometheus sink: %w", err))
+	}
+
+	go func() {
+		err := spew.Spew(context.Background(), c, io.Discard, sink)
+		if err != nil {
+			panic(err)
+		}
+	}()
+
+	http.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	if err := http.ListenAndServe(addr, nil); err != nil {
+		panic(fmt.Errorf("could not serve metrics: %w", err))
+	}
+}
@@ -0,0 +1,184 @@
+package spew
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/BurntSushi/toml"
+	"github.com/fsnotify/fsnotify"
+)
+
+// ConfigProvider supplies a Config and then streams updates to it, for
+// callers that want Spew to react to config changes rather than being
+// restarted. See SpewWithProvider.
+type ConfigProvider interface {
+	// Watch sends the current Config as soon as it's available, then an
+	// updated one each time the underlying source changes. Both
+	// channels are closed once ctx is done.
+	Watch(ctx context.Context) (<-chan Config, <-chan error)
+}
+
+// ConfigReader loads a Config from wherever a ConfigProvider gets its
+// configuration from.
+type ConfigReader func() (Config, error)
+
+// ReadTOMLConfig decodes a Config from a TOML file at path.
+func ReadTOMLConfig(path string) (Config, error) {
+	var c Config
+	_, err := toml.DecodeFile(path, &c)
+	if err != nil {
+		return Config{}, fmt.Errorf("could not read from config file(%s): %w", path, err)
+	}
+	return c, nil
+}
+
+// FileConfigProvider reads a TOML config from Path and re-reads it
+// whenever fsnotify reports the file changed.
+type FileConfigProvider struct {
+	Path string
+}
+
+// Watch implements ConfigProvider.
+func (p FileConfigProvider) Watch(ctx context.Context) (<-chan Config, <-chan error) {
+	configChan := make(chan Config)
+	errChan := make(chan error)
+
+	go func() {
+		defer close(configChan)
+		defer close(errChan)
+
+		c, err := ReadTOMLConfig(p.Path)
+		if err != nil {
+			select {
+			case <-ctx.Done():
+			case errChan <- err:
+			}
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case configChan <- c:
+		}
+
+		watcher, err := fsnotify.NewWatcher()
+		if err != nil {
+			select {
+			case <-ctx.Done():
+			case errChan <- fmt.Errorf("could not create watcher for config(%s): %w", p.Path, err):
+			}
+			return
+		}
+		defer watcher.Close()
+
+		if err := watcher.Add(p.Path); err != nil {
+			select {
+			case <-ctx.Done():
+			case errChan <- fmt.Errorf("could not watch config(%s): %w", p.Path, err):
+			}
+			return
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				select {
+				case <-ctx.Done():
+				case errChan <- err:
+				}
+				return
+			case ev, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if ev.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+					continue
+				}
+
+				c, err := ReadTOMLConfig(p.Path)
+				if err != nil {
+					select {
+					case <-ctx.Done():
+						return
+					case errChan <- err:
+					}
+					continue
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case configChan <- c:
+				}
+			}
+		}
+	}()
+
+	return configChan, errChan
+}
+
+// SIGHUPConfigProvider re-invokes Read for the initial Config and again
+// every time the process receives SIGHUP.
+type SIGHUPConfigProvider struct {
+	Read ConfigReader
+}
+
+// Watch implements ConfigProvider.
+func (p SIGHUPConfigProvider) Watch(ctx context.Context) (<-chan Config, <-chan error) {
+	configChan := make(chan Config)
+	errChan := make(chan error)
+
+	go func() {
+		defer close(configChan)
+		defer close(errChan)
+
+		c, err := p.Read()
+		if err != nil {
+			select {
+			case <-ctx.Done():
+			case errChan <- err:
+			}
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case configChan <- c:
+		}
+
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, syscall.SIGHUP)
+		defer signal.Stop(sigChan)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sigChan:
+				c, err := p.Read()
+				if err != nil {
+					select {
+					case <-ctx.Done():
+						return
+					case errChan <- err:
+					}
+					continue
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case configChan <- c:
+				}
+			}
+		}
+	}()
+
+	return configChan, errChan
+}
@@ -0,0 +1,83 @@
+package spew
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	FormatRaw   = "raw"
+	FormatJSON  = "json"
+	FormatKV    = "kv"
+	formatRegex = "regex:"
+)
+
+// decodeValue turns a source's raw output into the value stored under
+// its name in templateCtx, according to format (Source.Format). An
+// empty format behaves like FormatRaw: the sanitized string, unchanged.
+func decodeValue(raw, format string) (any, error) {
+	switch {
+	case format == "" || format == FormatRaw:
+		return sanitize(raw), nil
+	case format == FormatJSON:
+		var v any
+		if err := json.Unmarshal([]byte(raw), &v); err != nil {
+			return nil, fmt.Errorf("could not unmarshal json: %w", err)
+		}
+		return v, nil
+	case format == FormatKV:
+		return parseKV(raw), nil
+	case strings.HasPrefix(format, formatRegex):
+		return parseRegex(raw, strings.TrimPrefix(format, formatRegex))
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// parseKV parses "k=v" lines (optionally quoted values) into a map, as
+// produced by e.g. `env` or a script emitting KEY=VALUE pairs.
+func parseKV(raw string) map[string]string {
+	result := make(map[string]string)
+	for _, line := range strings.Split(raw, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		idx := strings.Index(line, "=")
+		if idx < 0 {
+			continue
+		}
+
+		k := strings.TrimSpace(line[:idx])
+		v := strings.TrimSpace(line[idx+1:])
+		v = strings.Trim(v, `"'`)
+		result[k] = v
+	}
+	return result
+}
+
+// parseRegex matches expr against raw and returns its named capture
+// groups as a map.
+func parseRegex(raw, expr string) (any, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("could not compile regex(%s): %w", expr, err)
+	}
+
+	m := re.FindStringSubmatch(raw)
+	result := make(map[string]string)
+	if m == nil {
+		return result, nil
+	}
+
+	for i, name := range re.SubexpNames() {
+		if i == 0 || name == "" {
+			continue
+		}
+		result[name] = m[i]
+	}
+	return result, nil
+}
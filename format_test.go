@@ -0,0 +1,101 @@
+package spew
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		format  string
+		want    any
+		wantErr bool
+	}{
+		{
+			name:   "empty format is raw, sanitized",
+			raw:    "hello\nworld\r\n",
+			format: "",
+			want:   "helloworld",
+		},
+		{
+			name:   "explicit raw",
+			raw:    "42\n",
+			format: FormatRaw,
+			want:   "42",
+		},
+		{
+			name:   "json object",
+			raw:    `{"Temp": 21.5, "City": "Nairobi"}`,
+			format: FormatJSON,
+			want:   map[string]any{"Temp": 21.5, "City": "Nairobi"},
+		},
+		{
+			name:    "invalid json",
+			raw:     `{not json`,
+			format:  FormatJSON,
+			wantErr: true,
+		},
+		{
+			name:   "kv pairs",
+			raw:    "Name=nginx\nStatus=\"running fine\"\n",
+			format: FormatKV,
+			want:   map[string]string{"Name": "nginx", "Status": "running fine"},
+		},
+		{
+			name:   "regex named groups",
+			raw:    "pod=web-1 status=Ready",
+			format: "regex:pod=(?P<Pod>\\S+) status=(?P<Status>\\S+)",
+			want:   map[string]string{"Pod": "web-1", "Status": "Ready"},
+		},
+		{
+			name:    "invalid regex",
+			raw:     "whatever",
+			format:  "regex:(",
+			wantErr: true,
+		},
+		{
+			name:    "unsupported format",
+			raw:     "whatever",
+			format:  "xml",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeValue(tt.raw, tt.format)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("decodeValue(%q, %q) returned no error, want one", tt.raw, tt.format)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeValue(%q, %q) returned error: %v", tt.raw, tt.format, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("decodeValue(%q, %q) = %#v, want %#v", tt.raw, tt.format, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseKVIgnoresLinesWithoutEquals(t *testing.T) {
+	got := parseKV("Name=nginx\njust some noise\nStatus=running")
+	want := map[string]string{"Name": "nginx", "Status": "running"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseKV = %#v, want %#v", got, want)
+	}
+}
+
+func TestParseRegexNoMatch(t *testing.T) {
+	got, err := parseRegex("no numbers here", `(?P<N>\d+)`)
+	if err != nil {
+		t.Fatalf("parseRegex returned error: %v", err)
+	}
+	if !reflect.DeepEqual(got, map[string]string{}) {
+		t.Fatalf("parseRegex = %#v, want empty map", got)
+	}
+}
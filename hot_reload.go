@@ -0,0 +1,176 @@
+package spew
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// runningSource tracks a started SourceRunner so a later reload can tell
+// whether it needs to be restarted and, if so, stop it.
+type runningSource struct {
+	source Source
+	cancel context.CancelFunc
+}
+
+// SpewWithProvider is like Spew, but takes its Config from provider
+// instead of a single fixed value, and reconciles a running render
+// against each update: sources whose configuration changed in any field
+// (see sourcesEqual) are canceled and recreated, new sources are started,
+// removed sources are
+// stopped, and already-collected values for unchanged source names are
+// kept across the reload.
+func SpewWithProvider(mainCtx context.Context, provider ConfigProvider, w io.Writer, sinks ...Sink) error {
+	ctx, cancel := context.WithCancel(mainCtx)
+	defer cancel()
+
+	configChan, configErrChan := provider.Watch(ctx)
+
+	var tmpl *template.Template
+	templateCtx := make(map[string]any)
+	formats := make(map[string]string)
+	bufw := bufio.NewWriter(w)
+
+	render := func() error {
+		if tmpl == nil {
+			return nil
+		}
+		if err := tmpl.Execute(bufw, templateCtx); err != nil {
+			return fmt.Errorf("could not render template: %w", err)
+		}
+		if err := bufw.Flush(); err != nil {
+			return fmt.Errorf("could not write to stdout: %w", err)
+		}
+		return nil
+	}
+
+	update := func(name, value string) error {
+		v, err := decodeValue(value, formats[name])
+		if err != nil {
+			return fmt.Errorf("could not decode value from source(%s): %w", name, err)
+		}
+		templateCtx[strings.Title(name)] = v
+
+		for _, sink := range sinks {
+			if err := sink.Update(name, sanitize(value)); err != nil {
+				return fmt.Errorf("could not update sink for source(%s): %w", name, err)
+			}
+		}
+		return nil
+	}
+
+	outChan := make(chan output)
+	errChan := make(chan error)
+	running := make(map[string]runningSource)
+	var renderDebounce time.Duration
+
+	reconcile := func(c Config) error {
+		tp, err := template.New("spew").Parse(c.Template)
+		if err != nil {
+			return fmt.Errorf("could not parse template: %w", err)
+		}
+		tmpl = tp
+		renderDebounce = c.RenderDebounce
+
+		wanted := make(map[string]Source, len(c.Sources))
+		for _, s := range c.Sources {
+			wanted[s.Name] = s
+			formats[s.Name] = s.Format
+		}
+
+		for name, r := range running {
+			if s, ok := wanted[name]; ok && sourcesEqual(s, r.source) {
+				continue
+			}
+			r.cancel()
+			delete(running, name)
+			delete(templateCtx, strings.Title(name))
+			delete(templateCtx, strings.Title(name)+"Err")
+		}
+
+		opts := SourceOptions{Logger: c.Logger}
+		for name, s := range wanted {
+			if _, ok := running[name]; ok {
+				continue
+			}
+
+			factory, err := lookupSource(s.Type)
+			if err != nil {
+				return err
+			}
+			runner, err := factory(s, opts)
+			if err != nil {
+				return fmt.Errorf("could not set up source(%s): %w", s.Name, err)
+			}
+
+			sctx, scancel := context.WithCancel(ctx)
+			running[name] = runningSource{source: s, cancel: scancel}
+
+			sourceOutChan := make(chan output)
+			go runner.Run(sctx, sourceOutChan, errChan)
+			go wrapMiddleware(sctx, s, sourceOutChan, outChan)
+		}
+
+		return render()
+	}
+
+	var renderTimer *time.Timer
+	var renderTimerC <-chan time.Time
+	dirty := false
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e := <-configErrChan:
+			return e
+		case c, ok := <-configChan:
+			if !ok {
+				configChan = nil
+				continue
+			}
+			if err := reconcile(c); err != nil {
+				return err
+			}
+		case e := <-errChan:
+			return e
+		case <-renderTimerC:
+			if !dirty {
+				continue
+			}
+			dirty = false
+			if err := render(); err != nil {
+				return err
+			}
+		case o := <-outChan:
+			if err := update(o.Name, o.Value); err != nil {
+				return err
+			}
+
+			if renderDebounce <= 0 {
+				if err := render(); err != nil {
+					return err
+				}
+				continue
+			}
+
+			dirty = true
+			if renderTimer == nil {
+				renderTimer = time.NewTimer(renderDebounce)
+			} else {
+				if !renderTimer.Stop() {
+					select {
+					case <-renderTimer.C:
+					default:
+					}
+				}
+				renderTimer.Reset(renderDebounce)
+			}
+			renderTimerC = renderTimer.C
+		}
+	}
+}
@@ -0,0 +1,12 @@
+package spew
+
+// Logger receives stderr lines from sources that would otherwise have
+// nowhere to go, such as a listen source's stderr once it no longer
+// aborts the whole run (see Source.Restart). Callers that don't set
+// Config.Logger still get those lines, under the "{Name}Err" template
+// key.
+type Logger interface {
+	// Errorf is called with a source's name and a line it wrote to
+	// stderr.
+	Errorf(sourceName, line string)
+}
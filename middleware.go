@@ -0,0 +1,110 @@
+package spew
+
+import (
+	"context"
+	"time"
+)
+
+// wrapMiddleware sits between a SourceRunner's own output channel (in)
+// and the shared one Spew reads from (out), applying Source.Debounce,
+// Source.MinInterval, and Source.EmitOnChange before a value is let
+// through. It runs until in is closed or ctx is done.
+func wrapMiddleware(ctx context.Context, s Source, in <-chan output, out chan<- output) {
+	if s.Debounce <= 0 && s.MinInterval <= 0 && !s.EmitOnChange {
+		// Nothing configured: pass values straight through.
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case o, ok := <-in:
+				if !ok {
+					return
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case out <- o:
+				}
+			}
+		}
+	}
+
+	var (
+		pending    *output
+		lastSent   string
+		hasSent    bool
+		lastSentAt time.Time
+		timer      *time.Timer
+		timerC     <-chan time.Time
+	)
+
+	flush := func() {
+		if pending == nil {
+			return
+		}
+		o := *pending
+		pending = nil
+
+		if s.EmitOnChange && hasSent && o.Value == lastSent {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case out <- o:
+		}
+		lastSent = o.Value
+		hasSent = true
+		lastSentAt = time.Now()
+	}
+
+	arm := func(d time.Duration) {
+		if timer == nil {
+			timer = time.NewTimer(d)
+		} else {
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			timer.Reset(d)
+		}
+		timerC = timer.C
+	}
+
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case o, ok := <-in:
+			if !ok {
+				flush()
+				return
+			}
+			pending = &o
+
+			delay := s.Debounce
+			if s.MinInterval > 0 && hasSent {
+				if wait := s.MinInterval - time.Since(lastSentAt); wait > delay {
+					delay = wait
+				}
+			}
+
+			if delay <= 0 {
+				flush()
+				continue
+			}
+			arm(delay)
+		case <-timerC:
+			flush()
+		}
+	}
+}
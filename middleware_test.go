@@ -0,0 +1,120 @@
+package spew
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func recvWithTimeout(t *testing.T, ch <-chan output, timeout time.Duration) (output, bool) {
+	t.Helper()
+	select {
+	case o := <-ch:
+		return o, true
+	case <-time.After(timeout):
+		return output{}, false
+	}
+}
+
+func expectNothing(t *testing.T, ch <-chan output, wait time.Duration) {
+	t.Helper()
+	select {
+	case o := <-ch:
+		t.Fatalf("expected no value within %s, got %+v", wait, o)
+	case <-time.After(wait):
+	}
+}
+
+func TestWrapMiddlewarePassthroughWhenUnconfigured(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	in := make(chan output)
+	out := make(chan output, 4)
+	go wrapMiddleware(ctx, Source{Name: "s"}, in, out)
+
+	in <- output{Name: "s", Value: "1"}
+	in <- output{Name: "s", Value: "2"}
+
+	o, ok := recvWithTimeout(t, out, time.Second)
+	if !ok || o.Value != "1" {
+		t.Fatalf("got %+v, %v, want Value=1", o, ok)
+	}
+	o, ok = recvWithTimeout(t, out, time.Second)
+	if !ok || o.Value != "2" {
+		t.Fatalf("got %+v, %v, want Value=2", o, ok)
+	}
+}
+
+func TestWrapMiddlewareEmitOnChangeDropsDuplicates(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	in := make(chan output)
+	out := make(chan output, 4)
+	go wrapMiddleware(ctx, Source{Name: "s", EmitOnChange: true}, in, out)
+
+	in <- output{Name: "s", Value: "same"}
+	o, ok := recvWithTimeout(t, out, time.Second)
+	if !ok || o.Value != "same" {
+		t.Fatalf("first value: got %+v, %v, want Value=same", o, ok)
+	}
+
+	in <- output{Name: "s", Value: "same"}
+	expectNothing(t, out, 200*time.Millisecond)
+
+	in <- output{Name: "s", Value: "different"}
+	o, ok = recvWithTimeout(t, out, time.Second)
+	if !ok || o.Value != "different" {
+		t.Fatalf("changed value: got %+v, %v, want Value=different", o, ok)
+	}
+}
+
+func TestWrapMiddlewareDebounceCoalescesBursts(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	in := make(chan output)
+	out := make(chan output, 4)
+	go wrapMiddleware(ctx, Source{Name: "s", Debounce: 80 * time.Millisecond}, in, out)
+
+	in <- output{Name: "s", Value: "1"}
+	time.Sleep(20 * time.Millisecond)
+	in <- output{Name: "s", Value: "2"}
+	time.Sleep(20 * time.Millisecond)
+	in <- output{Name: "s", Value: "3"}
+
+	// Still within the debounce window: nothing should have come through yet.
+	expectNothing(t, out, 40*time.Millisecond)
+
+	o, ok := recvWithTimeout(t, out, time.Second)
+	if !ok || o.Value != "3" {
+		t.Fatalf("got %+v, %v, want only the last value (3)", o, ok)
+	}
+	expectNothing(t, out, 150*time.Millisecond)
+}
+
+func TestWrapMiddlewareMinIntervalSpacesEmissions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	in := make(chan output)
+	out := make(chan output, 4)
+	go wrapMiddleware(ctx, Source{Name: "s", MinInterval: 150 * time.Millisecond}, in, out)
+
+	start := time.Now()
+	in <- output{Name: "s", Value: "1"}
+	o, ok := recvWithTimeout(t, out, time.Second)
+	if !ok || o.Value != "1" {
+		t.Fatalf("first value: got %+v, %v", o, ok)
+	}
+
+	in <- output{Name: "s", Value: "2"}
+	o, ok = recvWithTimeout(t, out, time.Second)
+	if !ok || o.Value != "2" {
+		t.Fatalf("second value: got %+v, %v", o, ok)
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Fatalf("second value emitted after only %s, want >= 150ms", elapsed)
+	}
+}
@@ -0,0 +1,146 @@
+package spew
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// MetricGauge and MetricCounter are the Prometheus metric types a source
+// can expose itself as via Source.Metric.
+const (
+	MetricGauge   = "gauge"
+	MetricCounter = "counter"
+)
+
+// PrometheusSink registers a gauge or counter per source (as declared by
+// Source.Metric) and keeps it up to date as values arrive on outChan.
+// Values that don't parse as a float64 are dropped with an error from
+// Update rather than panicking the source goroutine.
+//
+// A gauge source's value is taken as-is: each update overwrites it. A
+// counter source's value is instead expected to be an absolute,
+// monotonically increasing total (e.g. "requests served so far"); Update
+// derives the delta since the previous value and adds that, since
+// Prometheus counters only move forward. A negative value is rejected
+// rather than passed to Counter.Add, which panics on a negative
+// argument; a value lower than the last one is treated as the source
+// having reset (e.g. a restarted process) and is added as a fresh
+// baseline instead of going negative.
+type PrometheusSink struct {
+	registry *prometheus.Registry
+
+	mu         sync.Mutex
+	gauges     map[string]prometheus.Gauge
+	counters   map[string]prometheus.Counter
+	lastValues map[string]float64
+}
+
+// NewPrometheusSink builds a PrometheusSink that registers one metric per
+// source in sources, using Source.Name (sanitized and prefixed with
+// "spew_", see metricName) as the metric name and
+// Source.Metric to decide between a gauge and a counter. Source.MetricLabels,
+// if set, is registered as constant label values on that metric. Sources
+// with an empty Metric field are ignored.
+func NewPrometheusSink(registry *prometheus.Registry, sources []Source) (*PrometheusSink, error) {
+	sink := &PrometheusSink{
+		registry:   registry,
+		gauges:     make(map[string]prometheus.Gauge),
+		counters:   make(map[string]prometheus.Counter),
+		lastValues: make(map[string]float64),
+	}
+
+	for _, s := range sources {
+		labelNames, labelValues := splitLabels(s.MetricLabels)
+
+		switch s.Metric {
+		case "":
+			continue
+		case MetricGauge:
+			vec := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Name: metricName(s.Name),
+				Help: fmt.Sprintf("Latest value reported by spew source %q", s.Name),
+			}, labelNames)
+			if err := registry.Register(vec); err != nil {
+				return nil, fmt.Errorf("could not register gauge for source(%s): %w", s.Name, err)
+			}
+			sink.gauges[s.Name] = vec.WithLabelValues(labelValues...)
+		case MetricCounter:
+			vec := prometheus.NewCounterVec(prometheus.CounterOpts{
+				Name: metricName(s.Name),
+				Help: fmt.Sprintf("Latest value reported by spew source %q", s.Name),
+			}, labelNames)
+			if err := registry.Register(vec); err != nil {
+				return nil, fmt.Errorf("could not register counter for source(%s): %w", s.Name, err)
+			}
+			sink.counters[s.Name] = vec.WithLabelValues(labelValues...)
+		default:
+			return nil, fmt.Errorf("unsupported metric type for source(%s): %s", s.Name, s.Metric)
+		}
+	}
+
+	return sink, nil
+}
+
+// splitLabels turns a Source.MetricLabels map into the parallel
+// name/value slices *Vec constructors and WithLabelValues expect, with
+// names sorted so registration order is deterministic.
+func splitLabels(labels map[string]string) (names, values []string) {
+	names = make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	values = make([]string, len(names))
+	for i, name := range names {
+		values[i] = labels[name]
+	}
+	return names, values
+}
+
+// Update implements Sink.
+func (p *PrometheusSink) Update(name, value string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	g, isGauge := p.gauges[name]
+	c, isCounter := p.counters[name]
+	if !isGauge && !isCounter {
+		return nil
+	}
+
+	f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return fmt.Errorf("could not parse value(%s) from source(%s) as a number: %w", value, name, err)
+	}
+	if isGauge {
+		g.Set(f)
+	}
+	if isCounter {
+		if f < 0 {
+			return fmt.Errorf("value(%f) from source(%s) is negative, which is not valid for a counter", f, name)
+		}
+		last, ok := p.lastValues[name]
+		delta := f
+		if ok && f >= last {
+			delta = f - last
+		}
+		c.Add(delta)
+		p.lastValues[name] = f
+	}
+	return nil
+}
+
+var invalidMetricChars = regexp.MustCompile(`[^a-zA-Z0-9_:]`)
+
+// metricName derives a valid Prometheus metric name from a source name,
+// replacing anything outside [a-zA-Z0-9_:] with an underscore.
+func metricName(sourceName string) string {
+	return "spew_" + invalidMetricChars.ReplaceAllString(sourceName, "_")
+}
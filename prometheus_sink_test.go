@@ -0,0 +1,45 @@
+package spew
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestSplitLabelsSortsByName(t *testing.T) {
+	names, values := splitLabels(map[string]string{"zone": "us-east", "host": "web-1"})
+
+	if got, want := names, []string{"host", "zone"}; len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("names = %v, want %v", got, want)
+	}
+	if got, want := values, []string{"web-1", "us-east"}; len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("values = %v, want %v", got, want)
+	}
+}
+
+func TestSplitLabelsEmpty(t *testing.T) {
+	names, values := splitLabels(nil)
+	if len(names) != 0 || len(values) != 0 {
+		t.Fatalf("names = %v, values = %v, want both empty", names, values)
+	}
+}
+
+func TestPrometheusSinkGaugeWithLabels(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	sink, err := NewPrometheusSink(registry, []Source{
+		{Name: "temp", Metric: MetricGauge, MetricLabels: map[string]string{"room": "kitchen"}},
+	})
+	if err != nil {
+		t.Fatalf("NewPrometheusSink() error = %v", err)
+	}
+
+	if err := sink.Update("temp", "21.5"); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	got := testutil.ToFloat64(sink.gauges["temp"])
+	if got != 21.5 {
+		t.Fatalf("gauge value = %v, want 21.5", got)
+	}
+}
@@ -0,0 +1,59 @@
+package spew
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// SourceRunner runs a single configured Source, emitting values on out
+// and fatal errors on errs until ctx is canceled.
+type SourceRunner interface {
+	Run(ctx context.Context, out chan<- output, errs chan<- error)
+}
+
+// SourceOptions carries settings that apply across all sources in a
+// Spew call, as opposed to Source's per-source TOML fields.
+type SourceOptions struct {
+	// Logger, if set, receives stderr lines from sources that support
+	// logging them instead of treating them as fatal (see the listen
+	// source's Restart field).
+	Logger Logger
+}
+
+// SourceFactory builds a SourceRunner from a Source's configuration. It
+// is called once per Source when Spew starts; SpewWithProvider also calls
+// it again for each source that a reload adds or changes.
+type SourceFactory func(s Source, opts SourceOptions) (SourceRunner, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]SourceFactory)
+)
+
+// RegisterSource makes a source type available under typeName, so that
+// any Source.Type of typeName (optionally suffixed with ":<arg>", e.g.
+// "timer:30s") is built via factory. The built-in types are "timer",
+// "listen", "once", "fsnotify", "http", and "consul-kv"; third parties
+// can register their own, typically from an init() function.
+func RegisterSource(typeName string, factory SourceFactory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[typeName] = factory
+}
+
+func lookupSource(sourceType string) (SourceFactory, error) {
+	name := sourceType
+	if idx := strings.Index(name, ":"); idx >= 0 {
+		name = name[:idx]
+	}
+
+	registryMu.RLock()
+	factory, ok := registry[name]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unsupported source type: %s", sourceType)
+	}
+	return factory, nil
+}
@@ -0,0 +1,11 @@
+package spew
+
+// Sink receives the latest value for a source every time it updates,
+// in addition to whatever gets written to the template's io.Writer.
+// Sinks are a way to export source values to something other than a
+// rendered template, e.g. a metrics endpoint.
+type Sink interface {
+	// Update is called with a source's name and its latest sanitized
+	// value whenever a new value arrives on that source's out channel.
+	Update(name, value string) error
+}
@@ -0,0 +1,77 @@
+package spew
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/consul/api"
+)
+
+func init() {
+	RegisterSource("consul-kv", func(s Source, _ SourceOptions) (SourceRunner, error) {
+		if s.Script == "" {
+			return nil, fmt.Errorf("consul-kv source(%s) requires script to be the key to watch", s.Name)
+		}
+
+		client, err := api.NewClient(api.DefaultConfig())
+		if err != nil {
+			return nil, fmt.Errorf("could not build consul client: %w", err)
+		}
+
+		return &consulKVSource{source: s, kv: client.KV()}, nil
+	})
+}
+
+// consulKVSource streams updates to a Consul KV key (named by
+// Source.Script) using blocking queries, only emitting when the key's
+// Consul modify index actually advances — a blocking query also returns
+// when its timeout elapses with nothing new to say, and that return is
+// not mistaken for a change.
+type consulKVSource struct {
+	source Source
+	kv     *api.KV
+}
+
+func (cs *consulKVSource) Run(ctx context.Context, outChan chan<- output, errChan chan<- error) {
+	s := cs.source
+	opts := &api.QueryOptions{}
+	var lastIndex uint64
+	first := true
+
+	for {
+		pair, meta, err := cs.kv.Get(s.Script, opts.WithContext(ctx))
+		if err != nil {
+			select {
+			case <-ctx.Done():
+			case errChan <- fmt.Errorf("could not get consul key(%s): %w", s.Script, err):
+			}
+			return
+		}
+
+		// A blocking query's index can go backward (e.g. the key was
+		// deleted and recreated, or Consul's Raft index was reset);
+		// when it does, start waiting from scratch instead of getting
+		// stuck waiting past an index that will never recur.
+		if meta.LastIndex < lastIndex {
+			lastIndex = 0
+		}
+
+		if pair != nil && (first || meta.LastIndex != lastIndex) {
+			select {
+			case <-ctx.Done():
+				return
+			case outChan <- output{Name: s.Name, Value: string(pair.Value)}:
+			}
+		}
+
+		lastIndex = meta.LastIndex
+		first = false
+		opts = &api.QueryOptions{WaitIndex: lastIndex}
+
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+	}
+}
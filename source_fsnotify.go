@@ -0,0 +1,94 @@
+package spew
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+func init() {
+	RegisterSource("fsnotify", func(s Source, _ SourceOptions) (SourceRunner, error) {
+		if s.Script == "" {
+			return nil, fmt.Errorf("fsnotify source(%s) requires script to be the path to watch", s.Name)
+		}
+		return &fsnotifySource{source: s}, nil
+	})
+}
+
+// fsnotifySource watches the path named by Source.Script and emits that
+// file's contents whenever it is written to.
+type fsnotifySource struct {
+	source Source
+}
+
+func (f *fsnotifySource) Run(ctx context.Context, outChan chan<- output, errChan chan<- error) {
+	s := f.source
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		select {
+		case <-ctx.Done():
+		case errChan <- fmt.Errorf("could not create watcher for source(%s): %w", s.Name, err):
+		}
+		return
+	}
+	defer watcher.Close()
+
+	if err := watcher.Add(s.Script); err != nil {
+		select {
+		case <-ctx.Done():
+		case errChan <- fmt.Errorf("could not watch path(%s): %w", s.Script, err):
+		}
+		return
+	}
+
+	emit := func() bool {
+		b, err := os.ReadFile(s.Script)
+		if err != nil {
+			select {
+			case <-ctx.Done():
+			case errChan <- fmt.Errorf("could not read path(%s): %w", s.Script, err):
+			}
+			return false
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case outChan <- output{Name: s.Name, Value: string(b)}:
+			return true
+		}
+	}
+
+	if !emit() {
+		return
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			select {
+			case <-ctx.Done():
+			case errChan <- fmt.Errorf("watcher error for source(%s): %w", s.Name, err):
+			}
+			return
+		case ev, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if ev.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+			if !emit() {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package spew
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// minHTTPPollDelay is the floor on how often an http source re-fetches,
+// whether polling on a fixed interval or re-requesting immediately after
+// a long-poll response, so a misbehaving endpoint can't turn it into a
+// busy loop.
+const minHTTPPollDelay = time.Second
+
+// maxHTTPRetryBackoff caps the backoff applied between retries after a
+// failed fetch.
+const maxHTTPRetryBackoff = 30 * time.Second
+
+func init() {
+	RegisterSource("http", func(s Source, _ SourceOptions) (SourceRunner, error) {
+		if s.Script == "" {
+			return nil, fmt.Errorf("http source(%s) requires script to be the URL to fetch", s.Name)
+		}
+
+		var interval time.Duration
+		if parts := strings.SplitN(s.Type, ":", 2); len(parts) == 2 {
+			d, err := time.ParseDuration(parts[1])
+			if err != nil {
+				return nil, fmt.Errorf("could not parse duration(%s): %w", parts[1], err)
+			}
+			interval = d
+		}
+
+		return &httpSource{source: s, interval: interval}, nil
+	})
+}
+
+// httpSource fetches Source.Script (a URL) and emits the whole response
+// body once it's read in full. With an interval (e.g. "http:30s") it
+// polls on that schedule; without one it re-requests shortly after each
+// response, suiting a long-poll endpoint that blocks server-side until
+// there's something new to say and then returns a complete response.
+// It reads the body with io.ReadAll, so it does not stream an
+// incremental, connection-held-open protocol like SSE: an SSE endpoint
+// would just have its events buffered until the connection closes. A
+// failed fetch is treated as transient: it's retried with a growing
+// backoff rather than aborting the whole Spew run.
+type httpSource struct {
+	source   Source
+	interval time.Duration
+}
+
+func (h *httpSource) Run(ctx context.Context, outChan chan<- output, errChan chan<- error) {
+	delay := h.interval
+	if delay < minHTTPPollDelay {
+		delay = minHTTPPollDelay
+	}
+
+	backoff := minHTTPPollDelay
+	for {
+		body, err := h.fetch(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+
+			backoff *= 2
+			if backoff > maxHTTPRetryBackoff {
+				backoff = maxHTTPRetryBackoff
+			}
+			continue
+		}
+		backoff = minHTTPPollDelay
+
+		select {
+		case <-ctx.Done():
+			return
+		case outChan <- output{Name: h.source.Name, Value: body}:
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (h *httpSource) fetch(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.source.Script, nil)
+	if err != nil {
+		return "", fmt.Errorf("could not build request(%s): %w", h.source.Script, err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("could not fetch(%s): %w", h.source.Script, err)
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("could not read response body(%s): %w", h.source.Script, err)
+	}
+
+	return string(b), nil
+}
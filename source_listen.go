@@ -0,0 +1,214 @@
+package spew
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"os/exec"
+	"time"
+)
+
+const (
+	defaultBackoffInitial = time.Second
+	defaultBackoffMax     = 30 * time.Second
+	defaultStartSeconds   = time.Second
+)
+
+func init() {
+	RegisterSource("listen", func(s Source, opts SourceOptions) (SourceRunner, error) {
+		return &listenSource{source: s, logger: opts.Logger}, nil
+	})
+}
+
+// listenSource starts Source.Script and streams each stdout line as a
+// value. Stderr lines are routed to opts.Logger (or, absent one, emitted
+// under the "{Name}Err" template key) rather than ending the source.
+//
+// If Source.Restart is "always" or "on-failure", the command is
+// restarted with exponential backoff (Source.BackoffInitial up to
+// Source.BackoffMax, plus jitter) whenever it exits; exits faster than
+// Source.StartSeconds count as a failed attempt against
+// Source.MaxRetries, while a longer-lived run resets the counter. With
+// Restart empty, a single run behaves as before, just without stderr
+// aborting it.
+type listenSource struct {
+	source Source
+	logger Logger
+}
+
+func (l *listenSource) Run(ctx context.Context, outChan chan<- output, errChan chan<- error) {
+	s := l.source
+
+	backoffInitial := s.BackoffInitial
+	if backoffInitial <= 0 {
+		backoffInitial = defaultBackoffInitial
+	}
+	backoffMax := s.BackoffMax
+	if backoffMax <= 0 {
+		backoffMax = defaultBackoffMax
+	}
+	startSeconds := s.StartSeconds
+	if startSeconds <= 0 {
+		startSeconds = defaultStartSeconds
+	}
+
+	var retries int
+	for {
+		start := time.Now()
+		runErr := l.runOnce(ctx, outChan, errChan)
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		if s.Restart != "always" && s.Restart != "on-failure" {
+			if runErr != nil {
+				select {
+				case <-ctx.Done():
+				case errChan <- runErr:
+				}
+			}
+			return
+		}
+
+		if s.Restart == "on-failure" && runErr == nil {
+			return
+		}
+
+		if time.Since(start) < startSeconds {
+			retries++
+		} else {
+			retries = 0
+		}
+
+		if s.MaxRetries > 0 && retries > s.MaxRetries {
+			err := fmt.Errorf("source(%s) exceeded max retries(%d): %w", s.Name, s.MaxRetries, runErr)
+			select {
+			case <-ctx.Done():
+			case errChan <- err:
+			}
+			return
+		}
+
+		backoff := backoffWithJitter(retries, backoffInitial, backoffMax)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+	}
+}
+
+// runOnce starts the command, streams its stdout as values (and its
+// stderr lines via emitStderr) until either stream ends, and returns the
+// command's exit error (nil on a clean exit).
+//
+// Both stdout and stderr are read by their own readLines goroutine, and
+// runOnce returns as soon as the first of them reports EOF, so the other
+// is left blocked on its next read or on sending its own result. runOnce
+// therefore runs the pair under its own child context and cancels it
+// before returning, so whichever reader is still blocked unblocks via
+// <-ctx.Done() instead of leaking forever.
+func (l *listenSource) runOnce(ctx context.Context, outChan chan<- output, errChan chan<- error) error {
+	s := l.source
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	c := exec.CommandContext(runCtx, "/bin/sh", "-c", s.Script)
+
+	stdout, err := c.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("could not connect to command's stdout (%s): %w", s.Script, err)
+	}
+
+	stderr, err := c.StderrPipe()
+	if err != nil {
+		return fmt.Errorf("could not connect to command's stderr (%s): %w", s.Script, err)
+	}
+
+	if err := c.Start(); err != nil {
+		return fmt.Errorf("could not start command (%s): %w", s.Script, err)
+	}
+
+	stdoutChan, stdoutErrChan := readLines(runCtx, stdout)
+	stderrChan, stderrErrChan := readLines(runCtx, stderr)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-stdoutErrChan:
+			return c.Wait()
+		case <-stderrErrChan:
+			return c.Wait()
+		case line := <-stdoutChan:
+			select {
+			case <-ctx.Done():
+				return nil
+			case outChan <- output{Name: s.Name, Value: line}:
+			}
+		case line := <-stderrChan:
+			l.emitStderr(ctx, outChan, line)
+		}
+	}
+}
+
+func (l *listenSource) emitStderr(ctx context.Context, outChan chan<- output, line string) {
+	if l.logger != nil {
+		l.logger.Errorf(l.source.Name, line)
+		return
+	}
+
+	select {
+	case <-ctx.Done():
+	case outChan <- output{Name: l.source.Name + "Err", Value: line}:
+	}
+}
+
+// backoffExponential doubles initial once per retry, capped at max. It's
+// the deterministic half of backoffWithJitter, split out so it can be
+// tested without randomness.
+func backoffExponential(retries int, initial, max time.Duration) time.Duration {
+	backoff := initial * time.Duration(1<<retries)
+	if backoff > max || backoff <= 0 {
+		backoff = max
+	}
+	return backoff
+}
+
+// backoffWithJitter adds up to 50% random jitter on top of
+// backoffExponential, so that many sources failing at once don't all
+// retry in lockstep.
+func backoffWithJitter(retries int, initial, max time.Duration) time.Duration {
+	backoff := backoffExponential(retries, initial, max)
+	return backoff + time.Duration(rand.Int63n(int64(backoff)/2+1))
+}
+
+func readLines(ctx context.Context, r io.Reader) (<-chan string, <-chan error) {
+	outChan := make(chan string)
+	errChan := make(chan error)
+	go func() {
+		defer close(outChan)
+		defer close(errChan)
+		bufr := bufio.NewReader(r)
+		for {
+			line, err := bufr.ReadString('\n')
+			if err != nil {
+				select {
+				case <-ctx.Done():
+				case errChan <- fmt.Errorf("could not read from reader: %w", err):
+				}
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case outChan <- line:
+			}
+		}
+	}()
+	return outChan, errChan
+}
@@ -0,0 +1,113 @@
+package spew
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestBackoffExponential(t *testing.T) {
+	initial := time.Second
+	max := 30 * time.Second
+
+	tests := []struct {
+		retries int
+		want    time.Duration
+	}{
+		{retries: 0, want: 1 * time.Second},
+		{retries: 1, want: 2 * time.Second},
+		{retries: 2, want: 4 * time.Second},
+		{retries: 3, want: 8 * time.Second},
+		{retries: 4, want: 16 * time.Second},
+		{retries: 5, want: 30 * time.Second}, // would be 32s, capped at max
+		{retries: 10, want: 30 * time.Second},
+	}
+
+	for _, tt := range tests {
+		got := backoffExponential(tt.retries, initial, max)
+		if got != tt.want {
+			t.Errorf("backoffExponential(%d, %s, %s) = %s, want %s", tt.retries, initial, max, got, tt.want)
+		}
+	}
+}
+
+func TestListenSourceRestartEnforcesMaxRetries(t *testing.T) {
+	// With no StartSeconds set, the restart loop must still default it
+	// to something non-zero: otherwise a command that always exits
+	// instantly never counts as a fast-failing retry, and MaxRetries is
+	// never enforced.
+	s := Source{
+		Name:           "crashy",
+		Script:         "exit 1",
+		Restart:        "always",
+		MaxRetries:     2,
+		BackoffInitial: time.Millisecond,
+		BackoffMax:     2 * time.Millisecond,
+	}
+	l := &listenSource{source: s}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	outChan := make(chan output)
+	errChan := make(chan error, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		l.Run(ctx, outChan, errChan)
+	}()
+
+	select {
+	case err := <-errChan:
+		if err == nil {
+			t.Fatal("expected an error reporting exceeded max retries")
+		}
+	case <-ctx.Done():
+		t.Fatal("Run did not report exceeded max retries before MaxRetries enforcement timed out")
+	}
+
+	<-done
+}
+
+func TestListenSourceRunOnceDoesNotLeakGoroutines(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	l := &listenSource{source: Source{Name: "fast-exit", Script: "exit 0"}}
+	outChan := make(chan output)
+	errChan := make(chan error, 1)
+
+	for i := 0; i < 20; i++ {
+		if err := l.runOnce(ctx, outChan, errChan); err != nil {
+			t.Fatalf("runOnce returned error: %v", err)
+		}
+	}
+
+	// Give any leaked goroutines a moment to show up before counting.
+	time.Sleep(50 * time.Millisecond)
+	after := runtime.NumGoroutine()
+	if after > before+5 {
+		t.Fatalf("goroutine count grew from %d to %d after 20 runs, want it to stay roughly flat", before, after)
+	}
+}
+
+func TestBackoffWithJitterStaysWithinBounds(t *testing.T) {
+	initial := time.Second
+	max := 30 * time.Second
+
+	for retries := 0; retries < 8; retries++ {
+		base := backoffExponential(retries, initial, max)
+		for i := 0; i < 50; i++ {
+			got := backoffWithJitter(retries, initial, max)
+			if got < base {
+				t.Fatalf("backoffWithJitter(%d) = %s, want >= base %s", retries, got, base)
+			}
+			if got > base+base/2 {
+				t.Fatalf("backoffWithJitter(%d) = %s, want <= base*1.5 %s", retries, got, base+base/2)
+			}
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package spew
+
+import (
+	"context"
+	"fmt"
+)
+
+func init() {
+	RegisterSource("once", func(s Source, _ SourceOptions) (SourceRunner, error) {
+		return &onceSource{source: s}, nil
+	})
+}
+
+// onceSource runs Source.Script a single time and emits its output.
+type onceSource struct {
+	source Source
+}
+
+func (o *onceSource) Run(ctx context.Context, outChan chan<- output, errChan chan<- error) {
+	v, err := runCommand(ctx, o.source.Script)
+	if err != nil {
+		select {
+		case <-ctx.Done():
+		case errChan <- fmt.Errorf("could not run command(%s): %w: %q", o.source.Script, err, v):
+		}
+		return
+	}
+
+	select {
+	case <-ctx.Done():
+	case outChan <- output{Name: o.source.Name, Value: v}:
+	}
+}
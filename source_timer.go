@@ -0,0 +1,72 @@
+package spew
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+func init() {
+	RegisterSource("timer", func(s Source, _ SourceOptions) (SourceRunner, error) {
+		parts := strings.SplitN(s.Type, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("timer source requires a duration, e.g. timer:30s")
+		}
+
+		d, err := time.ParseDuration(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("could not parse duration(%s): %w", parts[1], err)
+		}
+
+		return &timerSource{source: s, interval: d}, nil
+	})
+}
+
+// timerSource runs Source.Script on a fixed interval, emitting an output
+// once immediately and again every tick thereafter.
+type timerSource struct {
+	source   Source
+	interval time.Duration
+}
+
+func (t *timerSource) Run(ctx context.Context, outChan chan<- output, errChan chan<- error) {
+	o, err := runCommand(ctx, t.source.Script)
+	if err != nil {
+		select {
+		case <-ctx.Done():
+		case errChan <- fmt.Errorf("could not run command(%s): %w: %q", t.source.Script, err, o):
+		}
+		return
+	}
+
+	select {
+	case <-ctx.Done():
+		return
+	case outChan <- output{Name: t.source.Name, Value: o}:
+	}
+
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			o, err := runCommand(ctx, t.source.Script)
+			if err != nil {
+				select {
+				case <-ctx.Done():
+				case errChan <- fmt.Errorf("could not run command(%s): %w: %q", t.source.Script, err, o):
+				}
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case outChan <- output{Name: t.source.Name, Value: o}:
+			}
+		}
+	}
+}
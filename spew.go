@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"maps"
 	"os/exec"
 	"strings"
 	"text/template"
@@ -15,6 +16,17 @@ import (
 type Config struct {
 	Template string   `toml:"template"`
 	Sources  []Source `toml:"sources"`
+
+	// RenderDebounce coalesces template renders: once a value arrives,
+	// Spew waits this long for quiet before rendering, so a burst of
+	// sources updating at once (e.g. several "once" sources at startup)
+	// produces one render instead of one per source.
+	RenderDebounce time.Duration `toml:"render_debounce"`
+
+	// Logger, if set, receives stderr lines from sources that support
+	// logging them (see Source.Restart). It has no TOML representation
+	// and must be set programmatically.
+	Logger Logger `toml:"-"`
 }
 
 // Source holds the configuration for a source
@@ -22,6 +34,48 @@ type Source struct {
 	Name   string `toml:"name"`
 	Type   string `toml:"type"`
 	Script string `toml:"script"`
+
+	// Metric, if set to "gauge" or "counter", exposes this source's
+	// values through any Sink passed to Spew (see PrometheusSink).
+	Metric string `toml:"metric"`
+	// MetricLabels attaches constant label values to Metric, e.g.
+	// {"host": "web-1"}. Labels are fixed at registration time, since a
+	// source only ever reports one value.
+	MetricLabels map[string]string `toml:"metric_labels"`
+
+	// Restart controls whether a listen source's command is restarted
+	// when it exits: "always" restarts unconditionally, "on-failure"
+	// only on a non-nil exit error, and "" (the default) never
+	// restarts.
+	Restart string `toml:"restart"`
+	// StartSeconds is how long the command must stay up for an exit to
+	// be treated as stable rather than a fast-failing retry attempt.
+	// Defaults to 1s; a zero or unset value does not mean "any exit is
+	// fast", since that would make MaxRetries unenforceable.
+	StartSeconds time.Duration `toml:"start_seconds"`
+	// MaxRetries caps consecutive fast-failing restarts before the
+	// source gives up and reports an error; 0 means unlimited.
+	MaxRetries int `toml:"max_retries"`
+	// BackoffInitial and BackoffMax bound the exponential backoff (plus
+	// jitter) applied between restarts. They default to 1s and 30s.
+	BackoffInitial time.Duration `toml:"backoff_initial"`
+	BackoffMax     time.Duration `toml:"backoff_max"`
+
+	// Format decodes this source's raw output before it reaches the
+	// template: "" and "raw" keep the sanitized string (the default),
+	// "json" unmarshals it, "kv" parses "k=v" lines into a map, and
+	// "regex:<expr>" maps expr's named capture groups. See decodeValue.
+	Format string `toml:"format"`
+
+	// Debounce coalesces a burst of values from this source into a
+	// single emission once Debounce has passed with no new value.
+	Debounce time.Duration `toml:"debounce"`
+	// MinInterval enforces a minimum gap between emitted values from
+	// this source, dropping the wait only once it has elapsed.
+	MinInterval time.Duration `toml:"min_interval"`
+	// EmitOnChange drops a value equal to the last one emitted for this
+	// source.
+	EmitOnChange bool `toml:"emit_on_change"`
 }
 
 type output struct {
@@ -29,15 +83,38 @@ type output struct {
 	Value string
 }
 
+// sourcesEqual reports whether a and b would start the same SourceRunner
+// with the same behavior. It exists because Source.MetricLabels is a map,
+// which makes Source no longer comparable with ==; the hot-reload
+// reconciler relies on this to detect unchanged sources.
+func sourcesEqual(a, b Source) bool {
+	return a.Name == b.Name &&
+		a.Type == b.Type &&
+		a.Script == b.Script &&
+		a.Metric == b.Metric &&
+		maps.Equal(a.MetricLabels, b.MetricLabels) &&
+		a.Restart == b.Restart &&
+		a.StartSeconds == b.StartSeconds &&
+		a.MaxRetries == b.MaxRetries &&
+		a.BackoffInitial == b.BackoffInitial &&
+		a.BackoffMax == b.BackoffMax &&
+		a.Format == b.Format &&
+		a.Debounce == b.Debounce &&
+		a.MinInterval == b.MinInterval &&
+		a.EmitOnChange == b.EmitOnChange
+}
+
 // Spew takes a configuration and an io.Writer and
-// writes its output to that io.Writer
-func Spew(mainCtx context.Context, c Config, w io.Writer) error {
+// writes its output to that io.Writer. Any sinks passed in are updated
+// with each source's latest sanitized value alongside the template
+// render.
+func Spew(mainCtx context.Context, c Config, w io.Writer, sinks ...Sink) error {
 	template, err := template.New("spew").Parse(c.Template)
 	if err != nil {
 		return fmt.Errorf("could not parse template: %w", err)
 	}
 
-	templateCtx := make(map[string]string)
+	templateCtx := make(map[string]any)
 	bufw := bufio.NewWriter(w)
 
 	render := func() error {
@@ -58,40 +135,86 @@ func Spew(mainCtx context.Context, c Config, w io.Writer) error {
 	ctx, cancel := context.WithCancel(mainCtx)
 	defer cancel()
 
+	formats := make(map[string]string, len(c.Sources))
 	for _, s := range c.Sources {
-		switch {
-		case strings.Contains(s.Type, "timer"):
-			go timerSource(ctx, s, outChan, errChan)
-		case strings.Contains(s.Type, "listen"):
-			go listenerSource(ctx, s, outChan, errChan)
-		case strings.Contains(s.Type, "once"):
-			o, err := runCommand(ctx, s.Script)
-			if err != nil {
-				return fmt.Errorf("could not run command(%s): %w: %q", s.Script, err, o)
-			}
+		formats[s.Name] = s.Format
+	}
 
-			templateCtx[strings.Title(s.Name)] = sanitize(o)
-			err = render()
-			if err != nil {
-				return err
+	update := func(name, value string) error {
+		v, err := decodeValue(value, formats[name])
+		if err != nil {
+			return fmt.Errorf("could not decode value from source(%s): %w", name, err)
+		}
+		templateCtx[strings.Title(name)] = v
+
+		for _, sink := range sinks {
+			if err := sink.Update(name, sanitize(value)); err != nil {
+				return fmt.Errorf("could not update sink for source(%s): %w", name, err)
 			}
-		default:
-			return fmt.Errorf("unsupported source type: %s", s.Type)
 		}
+		return nil
+	}
+
+	opts := SourceOptions{Logger: c.Logger}
+	for _, s := range c.Sources {
+		factory, err := lookupSource(s.Type)
+		if err != nil {
+			return err
+		}
+
+		runner, err := factory(s, opts)
+		if err != nil {
+			return fmt.Errorf("could not set up source(%s): %w", s.Name, err)
+		}
+
+		sourceOutChan := make(chan output)
+		go runner.Run(ctx, sourceOutChan, errChan)
+		go wrapMiddleware(ctx, s, sourceOutChan, outChan)
 	}
 
+	var renderTimer *time.Timer
+	var renderTimerC <-chan time.Time
+	dirty := false
+
 	for {
 		select {
 		case <-ctx.Done():
 			return nil
 		case e := <-errChan:
 			return e
+		case <-renderTimerC:
+			if !dirty {
+				continue
+			}
+			dirty = false
+			if err := render(); err != nil {
+				return err
+			}
 		case o := <-outChan:
-			templateCtx[strings.Title(o.Name)] = sanitize(o.Value)
-			err := render()
-			if err != nil {
+			if err := update(o.Name, o.Value); err != nil {
 				return err
 			}
+
+			if c.RenderDebounce <= 0 {
+				if err := render(); err != nil {
+					return err
+				}
+				continue
+			}
+
+			dirty = true
+			if renderTimer == nil {
+				renderTimer = time.NewTimer(c.RenderDebounce)
+			} else {
+				if !renderTimer.Stop() {
+					select {
+					case <-renderTimer.C:
+					default:
+					}
+				}
+				renderTimer.Reset(c.RenderDebounce)
+			}
+			renderTimerC = renderTimer.C
 		}
 	}
 }
@@ -108,143 +231,3 @@ func runCommand(ctx context.Context, cmd string) (string, error) {
 	outBytes, err := c.CombinedOutput()
 	return string(outBytes), err
 }
-
-func timerSource(ctx context.Context, s Source, outChan chan<- output, errChan chan<- error) {
-	parts := strings.Split(s.Type, ":")
-	d, err := time.ParseDuration(parts[1])
-	if err != nil {
-		select {
-		case <-ctx.Done():
-		case errChan <- fmt.Errorf("could not parse duration(%s): %w", parts[1], err):
-		}
-		return
-	}
-
-	o, err := runCommand(ctx, s.Script)
-	if err != nil {
-		select {
-		case <-ctx.Done():
-		case errChan <- fmt.Errorf("could not run command(%s): %w: %q", s.Script, err, o):
-		}
-		return
-	}
-
-	select {
-	case <-ctx.Done():
-		return
-	case outChan <- output{Name: s.Name, Value: o}:
-	}
-
-	ticker := time.NewTicker(d)
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			o, err := runCommand(ctx, s.Script)
-			if err != nil {
-				select {
-				case <-ctx.Done():
-				case errChan <- fmt.Errorf("could not run command(%s): %w: %q", s.Script, err, o):
-				}
-				return
-			}
-
-			select {
-			case <-ctx.Done():
-				return
-			case outChan <- output{Name: s.Name, Value: o}:
-			}
-		}
-	}
-}
-
-func listenerSource(ctx context.Context, s Source, outChan chan<- output, errChan chan<- error) {
-	c := exec.CommandContext(ctx, "/bin/sh", "-c", s.Script)
-
-	stdout, err := c.StdoutPipe()
-	if err != nil {
-		select {
-		case <-ctx.Done():
-		case errChan <- fmt.Errorf("could not connect to command's stdout (%s): %w", s.Script, err):
-		}
-		return
-	}
-
-	stderr, err := c.StderrPipe()
-	if err != nil {
-		select {
-		case <-ctx.Done():
-		case errChan <- fmt.Errorf("could not connect to command's stderr (%s): %w", s.Script, err):
-		}
-		return
-	}
-
-	err = c.Start()
-	if err != nil {
-		select {
-		case <-ctx.Done():
-		case errChan <- fmt.Errorf("could not start command (%s): %w", s.Script, err):
-		}
-		return
-	}
-
-	stdoutChan, stdoutErrChan := readLines(ctx, stdout)
-	stderrChan, stderrErrChan := readLines(ctx, stderr)
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case e := <-stdoutErrChan:
-			select {
-			case <-ctx.Done():
-			case errChan <- e:
-			}
-			return
-		case e := <-stderrErrChan:
-			select {
-			case <-ctx.Done():
-			case errChan <- e:
-			}
-			return
-		case line := <-stdoutChan:
-			select {
-			case <-ctx.Done():
-				return
-			case outChan <- output{Name: s.Name, Value: line}:
-			}
-		case line := <-stderrChan:
-			select {
-			case <-ctx.Done():
-			case outChan <- output{Name: s.Name, Value: line}:
-			}
-			return
-		}
-	}
-}
-
-func readLines(ctx context.Context, r io.Reader) (<-chan string, <-chan error) {
-	outChan := make(chan string)
-	errChan := make(chan error)
-	go func() {
-		defer close(outChan)
-		defer close(errChan)
-		bufr := bufio.NewReader(r)
-		for {
-			line, err := bufr.ReadString('\n')
-			if err != nil {
-				select {
-				case <-ctx.Done():
-				case errChan <- fmt.Errorf("could not read from reader: %w", err):
-				}
-				return
-			}
-			select {
-			case <-ctx.Done():
-				return
-			case outChan <- line:
-			}
-		}
-	}()
-	return outChan, errChan
-}